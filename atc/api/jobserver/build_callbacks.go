@@ -0,0 +1,125 @@
+package jobserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// CallbackID identifies a callback registered via BuildCallbackRegistry.
+type CallbackID string
+
+// CallbackOptions mirrors go-concourse/concourse.CallbackOptions on the
+// server side.
+type CallbackOptions struct {
+	Secret       string
+	RetryLimit   int
+	RetryBackoff time.Duration
+}
+
+// BuildCallbackRegistry is the dependency the build-callback handlers are
+// served through. Durably persisting and delivering callbacks is out of
+// scope here; this package only covers the HTTP surface the routes need.
+type BuildCallbackRegistry interface {
+	Register(pipelineName, jobName, callbackURL string, opts CallbackOptions) (CallbackID, error)
+	List(pipelineName, jobName string) ([]CallbackID, error)
+	Delete(pipelineName, jobName string, id CallbackID) (bool, error)
+}
+
+type registerBuildCallbackRequest struct {
+	CallbackURL  string        `json:"callback_url"`
+	Secret       string        `json:"secret,omitempty"`
+	RetryLimit   int           `json:"retry_limit,omitempty"`
+	RetryBackoff time.Duration `json:"retry_backoff,omitempty"`
+}
+
+type registerBuildCallbackResponse struct {
+	ID CallbackID `json:"id"`
+}
+
+// NewRegisterJobBuildCallbackHandler serves the RegisterJobBuildCallback
+// route.
+func NewRegisterJobBuildCallbackHandler(registry BuildCallbackRegistry) http.Handler {
+	return registerJobBuildCallbackHandler{registry: registry}
+}
+
+type registerJobBuildCallbackHandler struct {
+	registry BuildCallbackRegistry
+}
+
+func (h registerJobBuildCallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	pipelineName := r.URL.Query().Get(":pipeline_name")
+	jobName := r.URL.Query().Get(":job_name")
+
+	var request registerBuildCallbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	id, err := h.registry.Register(pipelineName, jobName, request.CallbackURL, CallbackOptions{
+		Secret:       request.Secret,
+		RetryLimit:   request.RetryLimit,
+		RetryBackoff: request.RetryBackoff,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(registerBuildCallbackResponse{ID: id})
+}
+
+// NewListJobBuildCallbacksHandler serves the ListJobBuildCallbacks route.
+func NewListJobBuildCallbacksHandler(registry BuildCallbackRegistry) http.Handler {
+	return listJobBuildCallbacksHandler{registry: registry}
+}
+
+type listJobBuildCallbacksHandler struct {
+	registry BuildCallbackRegistry
+}
+
+func (h listJobBuildCallbacksHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	pipelineName := r.URL.Query().Get(":pipeline_name")
+	jobName := r.URL.Query().Get(":job_name")
+
+	ids, err := h.registry.List(pipelineName, jobName)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(ids)
+}
+
+// NewDeleteJobBuildCallbackHandler serves the DeleteJobBuildCallback route.
+func NewDeleteJobBuildCallbackHandler(registry BuildCallbackRegistry) http.Handler {
+	return deleteJobBuildCallbackHandler{registry: registry}
+}
+
+type deleteJobBuildCallbackHandler struct {
+	registry BuildCallbackRegistry
+}
+
+func (h deleteJobBuildCallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	pipelineName := r.URL.Query().Get(":pipeline_name")
+	jobName := r.URL.Query().Get(":job_name")
+	id := CallbackID(r.URL.Query().Get(":callback_id"))
+
+	found, err := h.registry.Delete(pipelineName, jobName, id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,86 @@
+package jobserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/concourse/concourse/atc"
+)
+
+// JobBuildsFilter mirrors go-concourse/concourse.JobBuildsFilter on the
+// server side, parsed from the ListJobBuilds route's query string.
+type JobBuildsFilter struct {
+	Status      []atc.BuildStatus
+	Since       int
+	Until       int
+	TriggeredBy string
+}
+
+func filterFromRequest(r *http.Request) JobBuildsFilter {
+	query := r.URL.Query()
+
+	var filter JobBuildsFilter
+	for _, status := range query["status"] {
+		filter.Status = append(filter.Status, atc.BuildStatus(status))
+	}
+
+	filter.Since, _ = strconv.Atoi(query.Get("since"))
+	filter.Until, _ = strconv.Atoi(query.Get("until"))
+	filter.TriggeredBy = strings.TrimSpace(query.Get("triggered_by"))
+
+	return filter
+}
+
+// JobBuildsLister is the dependency NewListJobBuildsHandler's handler serves the
+// ListJobBuilds route through. It's responsible for actually applying the
+// filter against the DB; this package only parses it off the request.
+type JobBuildsLister interface {
+	JobBuilds(pipelineName, jobName string, page atc.Page, filter JobBuildsFilter) ([]atc.Build, atc.Pagination, error)
+}
+
+// NewListJobBuildsHandler serves the ListJobBuilds route, parsing the
+// status/since/until/triggered_by query params into a JobBuildsFilter so
+// they're honored rather than silently ignored.
+func NewListJobBuildsHandler(lister JobBuildsLister) http.Handler {
+	return listJobBuildsHandler{lister: lister}
+}
+
+type listJobBuildsHandler struct {
+	lister JobBuildsLister
+}
+
+func (h listJobBuildsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	pipelineName := r.URL.Query().Get(":pipeline_name")
+	jobName := r.URL.Query().Get(":job_name")
+
+	page := pageFromRequest(r)
+	filter := filterFromRequest(r)
+
+	builds, pagination, err := h.lister.JobBuilds(pipelineName, jobName, page, filter)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	pagination.SetHeaders(w.Header())
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(builds)
+}
+
+func pageFromRequest(r *http.Request) atc.Page {
+	query := r.URL.Query()
+
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	since, _ := strconv.Atoi(query.Get("since"))
+	until, _ := strconv.Atoi(query.Get("until"))
+
+	return atc.Page{
+		Limit: limit,
+		Since: since,
+		Until: until,
+	}
+}
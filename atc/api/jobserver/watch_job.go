@@ -0,0 +1,77 @@
+package jobserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/concourse/concourse/atc"
+)
+
+// JobBuildWatcher is the dependency NewWatchJobHandler's handler serves the
+// atc.WatchJob route through. The scheduler/DB layer implements it; this
+// package only depends on the interface.
+type JobBuildWatcher interface {
+	RecentBuilds(pipelineName, jobName string) ([]atc.Build, error)
+	WatchBuilds(pipelineName, jobName string) (events <-chan JobBuildTransitionEvent, cancel func())
+}
+
+// JobBuildTransitionEvent is a single build's status change, as pushed by
+// WatchBuilds and relayed to clients as a "patch" SSE event.
+type JobBuildTransitionEvent struct {
+	Transition string    `json:"transition"`
+	Build      atc.Build `json:"build"`
+}
+
+// NewWatchJobHandler serves the atc.WatchJob route: an SSE stream
+// delivering an initial snapshot of a job's recent builds followed by
+// incremental transitions, matching the "initial"/"patch" envelope the
+// go-concourse client already expects.
+func NewWatchJobHandler(watcher JobBuildWatcher) http.Handler {
+	return watchJobHandler{watcher: watcher}
+}
+
+type watchJobHandler struct {
+	watcher JobBuildWatcher
+}
+
+func (h watchJobHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	pipelineName := r.URL.Query().Get(":pipeline_name")
+	jobName := r.URL.Query().Get(":job_name")
+
+	builds, err := h.watcher.RecentBuilds(pipelineName, jobName)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	writeSSE(w, "initial", builds)
+	flusher.Flush()
+
+	events, cancel := h.watcher.WatchBuilds(pipelineName, jobName)
+	defer cancel()
+
+	for event := range events {
+		writeSSE(w, "patch", event)
+		flusher.Flush()
+	}
+}
+
+func writeSSE(w http.ResponseWriter, name string, payload interface{}) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, data)
+}
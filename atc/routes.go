@@ -0,0 +1,37 @@
+package atc
+
+import "github.com/tedsuo/rata"
+
+// Route names introduced alongside go-concourse/concourse/jobs.go. They
+// live in their own var rather than folded into the main route table so
+// this file merges cleanly with it.
+const (
+	WatchJob = "WatchJob"
+
+	RegisterJobBuildCallback = "RegisterJobBuildCallback"
+	ListJobBuildCallbacks    = "ListJobBuildCallbacks"
+	DeleteJobBuildCallback   = "DeleteJobBuildCallback"
+)
+
+var Routes = rata.Routes{
+	{
+		Name:   WatchJob,
+		Method: "GET",
+		Path:   "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/watch",
+	},
+	{
+		Name:   RegisterJobBuildCallback,
+		Method: "POST",
+		Path:   "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/callbacks",
+	},
+	{
+		Name:   ListJobBuildCallbacks,
+		Method: "GET",
+		Path:   "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/callbacks",
+	},
+	{
+		Name:   DeleteJobBuildCallback,
+		Method: "DELETE",
+		Path:   "/api/v1/teams/:team_name/pipelines/:pipeline_name/jobs/:job_name/callbacks/:callback_id",
+	},
+}
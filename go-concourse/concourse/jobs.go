@@ -1,13 +1,21 @@
 package concourse
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/concourse/concourse/atc"
 	"github.com/concourse/concourse/atc/api/jobserver"
+	"github.com/concourse/concourse/atc/event"
 	"github.com/concourse/concourse/go-concourse/concourse/internal"
 	"github.com/tedsuo/rata"
 	"github.com/vito/go-sse/sse"
@@ -102,6 +110,92 @@ func (client *client) WatchListAllJobs() (JobsEvents, error) {
 	return jobsEvents{sseEvents}, nil
 }
 
+// BuildTransition is the kind of status change a JobBuildWatchEvent reports.
+type BuildTransition string
+
+const (
+	BuildStarted  BuildTransition = "started"
+	BuildFinished BuildTransition = "finished"
+	BuildAborted  BuildTransition = "aborted"
+	BuildErrored  BuildTransition = "errored"
+)
+
+// JobBuildWatchEvent is a single build's status transition, as emitted by WatchJob.
+type JobBuildWatchEvent struct {
+	Transition BuildTransition `json:"transition"`
+	Build      atc.Build       `json:"build"`
+}
+
+type JobEvents interface {
+	Accept(visitor JobEventsVisitor) error
+	Close() error
+}
+
+type jobEvents struct {
+	src *sse.EventSource
+}
+
+//go:generate counterfeiter . JobEventsVisitor
+
+type JobEventsVisitor interface {
+	VisitInitialBuilds(builds []atc.Build) error
+	VisitBuildEvent(event JobBuildWatchEvent) error
+}
+
+func (j jobEvents) Accept(visitor JobEventsVisitor) error {
+	se, err := j.src.Next()
+	if err != nil {
+		return err
+	}
+	switch se.Name {
+	case "initial":
+		var builds []atc.Build
+		err := json.Unmarshal(se.Data, &builds)
+		if err != nil {
+			return err
+		}
+
+		return visitor.VisitInitialBuilds(builds)
+
+	case "patch":
+		var event JobBuildWatchEvent
+		err := json.Unmarshal(se.Data, &event)
+		if err != nil {
+			return err
+		}
+
+		return visitor.VisitBuildEvent(event)
+
+	default:
+		return fmt.Errorf("unknown event name: %s", se.Name)
+	}
+}
+
+func (j jobEvents) Close() error {
+	return j.src.Close()
+}
+
+// WatchJob opens an SSE stream of a job's recent builds and their status
+// transitions.
+func (team *team) WatchJob(pipelineName, jobName string) (JobEvents, error) {
+	params := rata.Params{
+		"pipeline_name": pipelineName,
+		"job_name":      jobName,
+		"team_name":     team.name,
+	}
+
+	sseEvents, err := team.connection.ConnectToEventStream(internal.Request{
+		RequestName: atc.WatchJob,
+		Params:      params,
+	})
+
+	if err != nil {
+		return jobEvents{}, err
+	}
+
+	return jobEvents{sseEvents}, nil
+}
+
 func (team *team) Job(pipelineName, jobName string) (atc.Job, bool, error) {
 	params := rata.Params{
 		"pipeline_name": pipelineName,
@@ -126,7 +220,46 @@ func (team *team) Job(pipelineName, jobName string) (atc.Job, bool, error) {
 	}
 }
 
-func (team *team) JobBuilds(pipelineName string, jobName string, page Page) ([]atc.Build, Pagination, bool, error) {
+// TriggeredBy narrows JobBuildsFilter to builds created a particular way.
+type TriggeredBy string
+
+const (
+	TriggeredByManual   TriggeredBy = "manual"
+	TriggeredByResource TriggeredBy = "resource"
+	TriggeredByRerun    TriggeredBy = "rerun"
+)
+
+// JobBuildsFilter narrows the builds returned by JobBuilds.
+type JobBuildsFilter struct {
+	Status      []atc.BuildStatus
+	Since       int
+	Until       int
+	TriggeredBy TriggeredBy
+}
+
+func (filter JobBuildsFilter) QueryParams() url.Values {
+	params := url.Values{}
+
+	for _, status := range filter.Status {
+		params.Add("status", string(status))
+	}
+
+	if filter.Since != 0 {
+		params.Set("since", strconv.Itoa(filter.Since))
+	}
+
+	if filter.Until != 0 {
+		params.Set("until", strconv.Itoa(filter.Until))
+	}
+
+	if filter.TriggeredBy != "" {
+		params.Set("triggered_by", string(filter.TriggeredBy))
+	}
+
+	return params
+}
+
+func (team *team) JobBuilds(pipelineName string, jobName string, page Page, filter JobBuildsFilter) ([]atc.Build, Pagination, bool, error) {
 	params := rata.Params{
 		"pipeline_name": pipelineName,
 		"job_name":      jobName,
@@ -135,11 +268,18 @@ func (team *team) JobBuilds(pipelineName string, jobName string, page Page) ([]a
 
 	var builds []atc.Build
 
+	query := page.QueryParams()
+	for key, values := range filter.QueryParams() {
+		for _, value := range values {
+			query.Add(key, value)
+		}
+	}
+
 	headers := http.Header{}
 	err := team.connection.Send(internal.Request{
 		RequestName: atc.ListJobBuilds,
 		Params:      params,
-		Query:       page.QueryParams(),
+		Query:       query,
 	}, &internal.Response{
 		Result:  &builds,
 		Headers: &headers,
@@ -159,6 +299,113 @@ func (team *team) JobBuilds(pipelineName string, jobName string, page Page) ([]a
 	}
 }
 
+// BuildLogEvents is a parsed view of a build's raw SSE event stream, split
+// out per step so consumers don't have to interpret the wire format
+// themselves.
+type BuildLogEvents interface {
+	Accept(visitor BuildLogEventsVisitor) error
+	Close() error
+}
+
+type buildLogEvents struct {
+	src         *sse.EventSource
+	maskSecrets []string
+}
+
+//go:generate counterfeiter . BuildLogEventsVisitor
+
+type BuildLogEventsVisitor interface {
+	VisitStepStarted(step string) error
+	VisitStepLog(step string, line string) error
+	VisitStepFinished(step string, status string) error
+	VisitError(err error) error
+}
+
+func (b buildLogEvents) Accept(visitor BuildLogEventsVisitor) error {
+	se, err := b.src.Next()
+	if err != nil {
+		return err
+	}
+
+	if se.Name != "event" {
+		return fmt.Errorf("unknown event name: %s", se.Name)
+	}
+
+	var envelope event.Envelope
+	if err := json.Unmarshal(se.Data, &envelope); err != nil {
+		return err
+	}
+
+	ev, err := event.ParseEvent(envelope.Version, envelope.Event, *envelope.Data)
+	if err != nil {
+		return err
+	}
+
+	switch e := ev.(type) {
+	case event.InitializeTask:
+		return visitor.VisitStepStarted(e.Origin.ID)
+	case event.InitializeGet:
+		return visitor.VisitStepStarted(e.Origin.ID)
+	case event.InitializePut:
+		return visitor.VisitStepStarted(e.Origin.ID)
+
+	case event.Log:
+		return visitor.VisitStepLog(e.Origin.ID, maskSecrets(e.Payload, b.maskSecrets))
+
+	case event.FinishTask:
+		return visitor.VisitStepFinished(e.Origin.ID, strconv.Itoa(e.ExitStatus))
+	case event.FinishGet:
+		return visitor.VisitStepFinished(e.Origin.ID, strconv.Itoa(e.ExitStatus))
+	case event.FinishPut:
+		return visitor.VisitStepFinished(e.Origin.ID, strconv.Itoa(e.ExitStatus))
+
+	case event.Error:
+		return visitor.VisitError(errors.New(e.Message))
+
+	default:
+		return nil
+	}
+}
+
+func (b buildLogEvents) Close() error {
+	return b.src.Close()
+}
+
+func maskSecrets(line string, secrets []string) string {
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+
+		line = strings.Replace(line, secret, "***", -1)
+	}
+
+	return line
+}
+
+// StreamJobBuildLogs opens the build's SSE event stream and parses it into
+// typed per-step records. Any occurrence of a secrets substring in an
+// emitted log line is replaced with "***" before the visitor sees it.
+func (team *team) StreamJobBuildLogs(pipelineName, jobName string, buildID int, secrets ...string) (BuildLogEvents, error) {
+	params := rata.Params{
+		"pipeline_name": pipelineName,
+		"job_name":      jobName,
+		"team_name":     team.name,
+		"build_id":      strconv.Itoa(buildID),
+	}
+
+	sseEvents, err := team.connection.ConnectToEventStream(internal.Request{
+		RequestName: atc.JobBuildEvents,
+		Params:      params,
+	})
+
+	if err != nil {
+		return buildLogEvents{}, err
+	}
+
+	return buildLogEvents{src: sseEvents, maskSecrets: secrets}, nil
+}
+
 func (team *team) PauseJob(pipelineName string, jobName string) (bool, error) {
 	params := rata.Params{
 		"pipeline_name": pipelineName,
@@ -225,6 +472,160 @@ func (team *team) ScheduleJob(pipelineName string, jobName string) (bool, error)
 	}
 }
 
+// JobAction is an operation BatchJobAction can apply.
+type JobAction string
+
+const (
+	JobActionPause    JobAction = "pause"
+	JobActionUnpause  JobAction = "unpause"
+	JobActionSchedule JobAction = "schedule"
+)
+
+// JobSelector picks the jobs a BatchJobAction applies to. Exactly one of
+// Names, Glob, or Tags should be set; Names takes precedence.
+type JobSelector struct {
+	Names []string `json:"names,omitempty"`
+	Glob  string   `json:"glob,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+// BatchJobStatus reports what happened to a single job in a BatchJobAction.
+type BatchJobStatus string
+
+const (
+	BatchJobSucceeded BatchJobStatus = "succeeded"
+	BatchJobSkipped   BatchJobStatus = "skipped"
+	BatchJobErrored   BatchJobStatus = "errored"
+)
+
+type BatchJobResult struct {
+	JobName string         `json:"job_name"`
+	Status  BatchJobStatus `json:"status"`
+	Error   string         `json:"error,omitempty"`
+}
+
+type BatchResult []BatchJobResult
+
+// batchJobActionConcurrency bounds how many jobs BatchJobAction acts on at
+// once, so a broad Glob/Tags selector can't open one connection per job.
+const batchJobActionConcurrency = 8
+
+// BatchJobAction applies action to every job matched by selector within a
+// pipeline, reporting a per-job result. There's no ATC endpoint for this
+// (it's pure client-side fan-out): the jobs are resolved via ListJobs, then
+// PauseJob/UnpauseJob/ScheduleJob is called for each match concurrently,
+// bounded by batchJobActionConcurrency.
+func (team *team) BatchJobAction(pipelineName string, action JobAction, selector JobSelector) (BatchResult, error) {
+	jobs, err := team.ListJobs(pipelineName)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := selectJobs(jobs, selector)
+
+	result := make(BatchResult, len(matched))
+	sem := make(chan struct{}, batchJobActionConcurrency)
+
+	var wg sync.WaitGroup
+	for i, job := range matched {
+		wg.Add(1)
+
+		go func(i int, jobName string) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			result[i] = BatchJobResult{
+				JobName: jobName,
+			}
+
+			found, err := team.runJobAction(pipelineName, jobName, action)
+			if err != nil {
+				result[i].Status = BatchJobErrored
+				result[i].Error = err.Error()
+				return
+			}
+
+			if !found {
+				result[i].Status = BatchJobSkipped
+				return
+			}
+
+			result[i].Status = BatchJobSucceeded
+		}(i, job.Name)
+	}
+	wg.Wait()
+
+	return result, nil
+}
+
+// runJobAction invokes the single-job method that action names.
+func (team *team) runJobAction(pipelineName, jobName string, action JobAction) (bool, error) {
+	switch action {
+	case JobActionPause:
+		return team.PauseJob(pipelineName, jobName)
+	case JobActionUnpause:
+		return team.UnpauseJob(pipelineName, jobName)
+	case JobActionSchedule:
+		return team.ScheduleJob(pipelineName, jobName)
+	default:
+		return false, fmt.Errorf("unknown job action: %s", action)
+	}
+}
+
+// selectJobs resolves a JobSelector against a pipeline's jobs. Names takes
+// precedence over Glob, which takes precedence over Tags.
+func selectJobs(jobs []atc.Job, selector JobSelector) []atc.Job {
+	if len(selector.Names) > 0 {
+		byName := make(map[string]atc.Job, len(jobs))
+		for _, job := range jobs {
+			byName[job.Name] = job
+		}
+
+		var matched []atc.Job
+		for _, name := range selector.Names {
+			if job, ok := byName[name]; ok {
+				matched = append(matched, job)
+			}
+		}
+
+		return matched
+	}
+
+	if selector.Glob != "" {
+		var matched []atc.Job
+		for _, job := range jobs {
+			if ok, _ := path.Match(selector.Glob, job.Name); ok {
+				matched = append(matched, job)
+			}
+		}
+
+		return matched
+	}
+
+	if len(selector.Tags) > 0 {
+		wanted := make(map[string]bool, len(selector.Tags))
+		for _, tag := range selector.Tags {
+			wanted[tag] = true
+		}
+
+		var matched []atc.Job
+		for _, job := range jobs {
+			for _, group := range job.Groups {
+				if wanted[group] {
+					matched = append(matched, job)
+					break
+				}
+			}
+		}
+
+		return matched
+	}
+
+	return nil
+}
+
 func (team *team) ClearTaskCache(pipelineName string, jobName string, stepName string, cachePath string) (int64, error) {
 	params := rata.Params{
 		"team_name":     team.name,
@@ -256,3 +657,179 @@ func (team *team) ClearTaskCache(pipelineName string, jobName string, stepName s
 		return ctcResponse.CachesRemoved, nil
 	}
 }
+
+// CancelFunc stops the goroutine started by OnBuildComplete. It is safe to
+// call more than once.
+type CancelFunc func()
+
+type onBuildCompleteVisitor struct {
+	cb func(atc.Build, error)
+}
+
+// VisitInitialBuilds discards the snapshot WatchJob delivers on connect, so
+// a build already terminal at subscribe time never reaches cb. Only builds
+// that transition to a terminal state after the stream opens do.
+func (v onBuildCompleteVisitor) VisitInitialBuilds(builds []atc.Build) error {
+	return nil
+}
+
+func (v onBuildCompleteVisitor) VisitBuildEvent(event JobBuildWatchEvent) error {
+	switch event.Transition {
+	case BuildFinished, BuildAborted, BuildErrored:
+		v.cb(event.Build, nil)
+	}
+
+	return nil
+}
+
+// OnBuildComplete watches a job and invokes cb once for every build of that
+// job that reaches a terminal state after the subscription starts, until
+// the returned CancelFunc is called. A build already terminal when
+// OnBuildComplete is called is not delivered. It holds an SSE connection
+// open for the lifetime of the subscription; callers that can't do that
+// should use RegisterBuildCallback instead.
+func (team *team) OnBuildComplete(pipelineName, jobName string, cb func(atc.Build, error)) (CancelFunc, error) {
+	events, err := team.WatchJob(pipelineName, jobName)
+	if err != nil {
+		return nil, err
+	}
+
+	return watchUntilComplete(events, cb), nil
+}
+
+func watchUntilComplete(events JobEvents, cb func(atc.Build, error)) CancelFunc {
+	done := make(chan struct{})
+	var closeOnce sync.Once
+	cancel := func() {
+		closeOnce.Do(func() {
+			close(done)
+			events.Close()
+		})
+	}
+
+	go func() {
+		defer cancel()
+
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			err := events.Accept(onBuildCompleteVisitor{cb: cb})
+			if err != nil {
+				select {
+				case <-done:
+					// cancelled; events.Close() is what unblocked Accept
+				default:
+					cb(atc.Build{}, err)
+				}
+				return
+			}
+		}
+	}()
+
+	return cancel
+}
+
+// CallbackID identifies a callback registered via RegisterBuildCallback.
+type CallbackID string
+
+// CallbackOptions configures how ATC delivers a registered build callback.
+type CallbackOptions struct {
+	Secret       string
+	RetryLimit   int
+	RetryBackoff time.Duration
+}
+
+type registerBuildCallbackRequest struct {
+	CallbackURL  string        `json:"callback_url"`
+	Secret       string        `json:"secret,omitempty"`
+	RetryLimit   int           `json:"retry_limit,omitempty"`
+	RetryBackoff time.Duration `json:"retry_backoff,omitempty"`
+}
+
+type registerBuildCallbackResponse struct {
+	ID CallbackID `json:"id"`
+}
+
+// RegisterBuildCallback asks ATC to durably POST a signed payload to
+// callbackURL, with retry/backoff per opts, whenever a build of this job
+// reaches a terminal state. Unlike OnBuildComplete, the registration
+// survives the calling process exiting.
+func (team *team) RegisterBuildCallback(pipelineName, jobName, callbackURL string, opts CallbackOptions) (CallbackID, error) {
+	params := rata.Params{
+		"pipeline_name": pipelineName,
+		"job_name":      jobName,
+		"team_name":     team.name,
+	}
+
+	body, err := json.Marshal(registerBuildCallbackRequest{
+		CallbackURL:  callbackURL,
+		Secret:       opts.Secret,
+		RetryLimit:   opts.RetryLimit,
+		RetryBackoff: opts.RetryBackoff,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var response registerBuildCallbackResponse
+	err = team.connection.Send(internal.Request{
+		RequestName: atc.RegisterJobBuildCallback,
+		Params:      params,
+		Body:        bytes.NewBuffer(body),
+		Header:      http.Header{"Content-Type": {"application/json"}},
+	}, &internal.Response{
+		Result: &response,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return response.ID, nil
+}
+
+// ListBuildCallbacks lists the callbacks registered for a job.
+func (team *team) ListBuildCallbacks(pipelineName, jobName string) ([]CallbackID, error) {
+	params := rata.Params{
+		"pipeline_name": pipelineName,
+		"job_name":      jobName,
+		"team_name":     team.name,
+	}
+
+	var ids []CallbackID
+	err := team.connection.Send(internal.Request{
+		RequestName: atc.ListJobBuildCallbacks,
+		Params:      params,
+	}, &internal.Response{
+		Result: &ids,
+	})
+
+	return ids, err
+}
+
+// DeleteBuildCallback removes a previously registered callback.
+func (team *team) DeleteBuildCallback(pipelineName, jobName string, id CallbackID) (bool, error) {
+	params := rata.Params{
+		"pipeline_name": pipelineName,
+		"job_name":      jobName,
+		"team_name":     team.name,
+		"callback_id":   string(id),
+	}
+
+	err := team.connection.Send(internal.Request{
+		RequestName: atc.DeleteJobBuildCallback,
+		Params:      params,
+	}, &internal.Response{})
+
+	switch err.(type) {
+	case nil:
+		return true, nil
+	case internal.ResourceNotFoundError:
+		return false, nil
+	default:
+		return false, err
+	}
+}
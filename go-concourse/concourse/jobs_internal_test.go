@@ -0,0 +1,142 @@
+package concourse
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/concourse/concourse/atc"
+)
+
+func TestMaskSecrets(t *testing.T) {
+	tests := []struct {
+		line    string
+		secrets []string
+		want    string
+	}{
+		{"deploying with token s3kret", []string{"s3kret"}, "deploying with token ***"},
+		{"nothing to mask here", []string{"s3kret"}, "nothing to mask here"},
+		{"s3kret s3kret", []string{"s3kret"}, "*** ***"},
+		{"user=admin pass=hunter2", []string{"admin", "hunter2"}, "user=*** pass=***"},
+		{"unchanged", nil, "unchanged"},
+		{"skips empty secret", []string{""}, "skips empty secret"},
+	}
+
+	for _, tt := range tests {
+		if got := maskSecrets(tt.line, tt.secrets); got != tt.want {
+			t.Errorf("maskSecrets(%q, %v) = %q, want %q", tt.line, tt.secrets, got, tt.want)
+		}
+	}
+}
+
+// fakeJobEvents blocks in Accept until Close is called, the same way a real
+// jobEvents blocks in sse.EventSource.Next() waiting on the connection.
+type fakeJobEvents struct {
+	mu         sync.Mutex
+	closed     bool
+	closedCh   chan struct{}
+	closeCalls int
+	entered    chan struct{}
+	accepted   chan struct{}
+}
+
+func newFakeJobEvents() *fakeJobEvents {
+	return &fakeJobEvents{
+		closedCh: make(chan struct{}),
+		entered:  make(chan struct{}, 1),
+		accepted: make(chan struct{}, 1),
+	}
+}
+
+func (f *fakeJobEvents) Accept(visitor JobEventsVisitor) error {
+	f.entered <- struct{}{}
+	<-f.closedCh
+	f.accepted <- struct{}{}
+	return errors.New("connection closed")
+}
+
+func (f *fakeJobEvents) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closeCalls++
+	if !f.closed {
+		f.closed = true
+		close(f.closedCh)
+	}
+
+	return nil
+}
+
+func TestWatchUntilCompleteCancelUnblocksAndIsQuiet(t *testing.T) {
+	events := newFakeJobEvents()
+
+	var cbCalls int32
+	cancel := watchUntilComplete(events, func(atc.Build, error) {
+		atomic.AddInt32(&cbCalls, 1)
+	})
+
+	select {
+	case <-events.entered:
+	case <-time.After(time.Second):
+		t.Fatal("Accept was never called")
+	}
+
+	cancel()
+
+	select {
+	case <-events.accepted:
+	case <-time.After(time.Second):
+		t.Fatal("cancel did not unblock the pending Accept call")
+	}
+
+	if events.closeCalls != 1 {
+		t.Errorf("events.Close() called %d times, want 1", events.closeCalls)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	if atomic.LoadInt32(&cbCalls) != 0 {
+		t.Errorf("cancel delivered a spurious callback")
+	}
+
+	cancel()
+	if events.closeCalls != 1 {
+		t.Errorf("events.Close() called %d times after repeat cancel, want 1", events.closeCalls)
+	}
+}
+
+type erroringJobEvents struct {
+	err error
+}
+
+func (e erroringJobEvents) Accept(visitor JobEventsVisitor) error {
+	return e.err
+}
+
+func (e erroringJobEvents) Close() error {
+	return nil
+}
+
+func TestWatchUntilCompleteDeliversStreamErrors(t *testing.T) {
+	streamErr := errors.New("boom")
+
+	done := make(chan struct{})
+	var gotErr error
+	cancel := watchUntilComplete(erroringJobEvents{err: streamErr}, func(build atc.Build, err error) {
+		gotErr = err
+		close(done)
+	})
+	defer cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("callback was never invoked for a non-cancel stream error")
+	}
+
+	if gotErr != streamErr {
+		t.Errorf("gotErr = %v, want %v", gotErr, streamErr)
+	}
+}
@@ -0,0 +1,111 @@
+package concourse_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/concourse/concourse/atc"
+	"github.com/concourse/concourse/go-concourse/concourse"
+)
+
+func TestJobBuildsFilterQueryParams(t *testing.T) {
+	filter := concourse.JobBuildsFilter{
+		Status:      []atc.BuildStatus{atc.StatusFailed, atc.StatusErrored},
+		Since:       100,
+		Until:       200,
+		TriggeredBy: concourse.TriggeredByManual,
+	}
+
+	query := filter.QueryParams()
+
+	if got := query["status"]; len(got) != 2 || got[0] != "failed" || got[1] != "errored" {
+		t.Errorf("status = %v, want [failed errored]", got)
+	}
+
+	if got := query.Get("since"); got != "100" {
+		t.Errorf("since = %q, want %q", got, "100")
+	}
+
+	if got := query.Get("until"); got != "200" {
+		t.Errorf("until = %q, want %q", got, "200")
+	}
+
+	if got := query.Get("triggered_by"); got != "manual" {
+		t.Errorf("triggered_by = %q, want %q", got, "manual")
+	}
+}
+
+func TestJobBuildsFilterQueryParamsEmpty(t *testing.T) {
+	query := concourse.JobBuildsFilter{}.QueryParams()
+
+	if len(query) != 0 {
+		t.Errorf("query = %v, want empty", query)
+	}
+}
+
+func TestJobBuildWatchEventJSON(t *testing.T) {
+	event := concourse.JobBuildWatchEvent{
+		Transition: concourse.BuildFinished,
+		Build:      atc.Build{ID: 42},
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var roundTripped concourse.JobBuildWatchEvent
+	if err := json.Unmarshal(payload, &roundTripped); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if roundTripped.Transition != concourse.BuildFinished {
+		t.Errorf("Transition = %q, want %q", roundTripped.Transition, concourse.BuildFinished)
+	}
+
+	if roundTripped.Build.ID != 42 {
+		t.Errorf("Build.ID = %d, want 42", roundTripped.Build.ID)
+	}
+}
+
+func TestJobSelectorJSONTags(t *testing.T) {
+	payload, err := json.Marshal(concourse.JobSelector{
+		Names: []string{"unit"},
+		Glob:  "deploy-*",
+		Tags:  []string{"maintenance"},
+	})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	for _, key := range []string{"names", "glob", "tags"} {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("expected snake_case key %q in %s", key, payload)
+		}
+	}
+}
+
+func TestJobSelectorJSONTagsOmitEmpty(t *testing.T) {
+	payload, err := json.Marshal(concourse.JobSelector{Names: []string{"unit"}})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if _, ok := fields["glob"]; ok {
+		t.Errorf("expected glob to be omitted when empty, got %s", payload)
+	}
+
+	if _, ok := fields["tags"]; ok {
+		t.Errorf("expected tags to be omitted when empty, got %s", payload)
+	}
+}